@@ -13,6 +13,21 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"strings"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+var (
+	deepCompare       bool
+	compareTablesFlag []string
+	compareFromKey    string
+	compareToKey      string
+	compareMaxDiffs   int
+	compareReportPath string
 )
 
 var stateStagesZk = &cobra.Command{
@@ -21,6 +36,7 @@ var stateStagesZk = &cobra.Command{
 Examples:
 state_stages_zkevm --datadir=/datadirs/hermez-mainnet--unwind-batch-no=10  # unwind so the tip is the highest block in batch number 10
 state_stages_zkevm --datadir=/datadirs/hermez-mainnet --unwind-batch-no=2 --chain=hermez-bali --log.console.verbosity=4 --datadir-compare=/datadirs/pre-synced-block-100 # unwind to batch 2 and compare with another datadir
+state_stages_zkevm --datadir=/datadirs/hermez-mainnet --unwind-batch-no=2 --datadir-compare=/datadirs/pre-synced-block-100 --deep-compare --compare-tables=PlainState,HashedAccounts --compare-report=/tmp/diff.json # diff values, not just counts
 		`,
 	Example: "go run ./cmd/integration state_stages_zkevm --config=... --verbosity=3 --unwind-batch-no=100",
 	Run: func(cmd *cobra.Command, args []string) {
@@ -42,11 +58,25 @@ state_stages_zkevm --datadir=/datadirs/hermez-mainnet --unwind-batch-no=2 --chai
 			dbCompare := openDB(dbCfg(kv.ChainDB, filepath.Join(datadirCompare, "chaindata")), true)
 			defer dbCompare.Close()
 
-			diff, err := compareDbs(db, dbCompare)
+			opts, err := newCompareOptions()
 			if err != nil {
 				log.Error(err.Error())
 				return
 			}
+
+			diff, report, err := compareDbs(db, dbCompare, opts)
+			if err != nil {
+				log.Error(err.Error())
+				return
+			}
+
+			if len(compareReportPath) > 0 {
+				if err := writeCompareReport(report, compareReportPath); err != nil {
+					log.Error(err.Error())
+					return
+				}
+			}
+
 			if len(diff) > 0 {
 				log.Error("Databases are different")
 				for _, d := range diff {
@@ -65,6 +95,14 @@ func init() {
 	withDataDirCompare(stateStagesZk)
 	withUnwind(stateStagesZk)
 	withUnwindBatchNo(stateStagesZk) // populates package global flag unwindBatchNo
+
+	stateStagesZk.Flags().BoolVar(&deepCompare, "deep-compare", false, "walk every key/value pair instead of just comparing per-table key counts")
+	stateStagesZk.Flags().StringSliceVar(&compareTablesFlag, "compare-tables", nil, "restrict the comparison (count or --deep-compare) to this comma-separated list of tables (default: all non-excluded tables)")
+	stateStagesZk.Flags().StringVar(&compareFromKey, "compare-from-key", "", "hex-encoded key to start the deep compare from (per table)")
+	stateStagesZk.Flags().StringVar(&compareToKey, "compare-to-key", "", "hex-encoded key to stop the deep compare at, inclusive (per table)")
+	stateStagesZk.Flags().IntVar(&compareMaxDiffs, "compare-max-diffs", 100, "max number of differing keys to record per table, 0 means unlimited")
+	stateStagesZk.Flags().StringVar(&compareReportPath, "compare-report", "", "write a machine-readable JSON diff report to this path")
+
 	rootCmd.AddCommand(stateStagesZk)
 }
 
@@ -97,9 +135,116 @@ func unwindZk(ctx context.Context, db kv.RwDB) error {
 	return nil
 }
 
-func compareDbs(db1, db2 kv.RwDB) ([]string, error) {
+// compareOptions controls how compareDbs walks the two databases.
+type compareOptions struct {
+	deep     bool
+	tables   map[string]struct{}
+	fromKey  []byte
+	toKey    []byte
+	maxDiffs int
+}
+
+func newCompareOptions() (compareOptions, error) {
+	opts := compareOptions{
+		deep:     deepCompare,
+		maxDiffs: compareMaxDiffs,
+	}
+
+	if len(compareTablesFlag) > 0 {
+		opts.tables = make(map[string]struct{}, len(compareTablesFlag))
+		for _, t := range compareTablesFlag {
+			opts.tables[t] = struct{}{}
+		}
+	}
+
+	if len(compareFromKey) > 0 {
+		k, err := hex.DecodeString(strings.TrimPrefix(compareFromKey, "0x"))
+		if err != nil {
+			return opts, fmt.Errorf("invalid --compare-from-key: %w", err)
+		}
+		opts.fromKey = k
+	}
+
+	if len(compareToKey) > 0 {
+		k, err := hex.DecodeString(strings.TrimPrefix(compareToKey, "0x"))
+		if err != nil {
+			return opts, fmt.Errorf("invalid --compare-to-key: %w", err)
+		}
+		opts.toKey = k
+	}
+
+	return opts, nil
+}
+
+// KeyDiff is a single differing key, recorded with hex-encoded values so the
+// report stays valid JSON regardless of table content.
+type KeyDiff struct {
+	Key      string `json:"key"`
+	Kind     string `json:"kind"` // "added", "removed" or "changed"
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+	Decoded  string `json:"decoded,omitempty"`
+}
+
+// TableDiff summarises the differences found for a single table. CountOnly is
+// set when the comparison fell back to the original key-count-only mode.
+type TableDiff struct {
+	Table       string    `json:"table"`
+	CountOnly   bool      `json:"count_only,omitempty"`
+	Count1      uint64    `json:"count1,omitempty"`
+	Count2      uint64    `json:"count2,omitempty"`
+	Added       uint64    `json:"added,omitempty"`
+	Removed     uint64    `json:"removed,omitempty"`
+	Changed     uint64    `json:"changed,omitempty"`
+	SampleDiffs []KeyDiff `json:"sample_diffs,omitempty"`
+}
+
+func (d *TableDiff) addSample(maxDiffs int, kd KeyDiff) {
+	if maxDiffs > 0 && len(d.SampleDiffs) >= maxDiffs {
+		return
+	}
+	d.SampleDiffs = append(d.SampleDiffs, kd)
+}
+
+// CompareSummary aggregates the per-table diffs into totals for a quick CI gate check.
+type CompareSummary struct {
+	TablesCompared  int    `json:"tables_compared"`
+	TablesDiffering int    `json:"tables_differing"`
+	Added           uint64 `json:"added,omitempty"`
+	Removed         uint64 `json:"removed,omitempty"`
+	Changed         uint64 `json:"changed,omitempty"`
+}
+
+// CompareReport is the machine-readable output of compareDbs, written to
+// --compare-report so CI can gate on unwind correctness.
+type CompareReport struct {
+	Mode    string         `json:"mode"` // "count" or "deep"
+	Tables  []*TableDiff   `json:"tables,omitempty"`
+	Summary CompareSummary `json:"summary"`
+}
+
+func writeCompareReport(report *CompareReport, path string) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshalling compare report: %w", err)
+	}
+	if err := os.WriteFile(path, b, 0644); err != nil {
+		return fmt.Errorf("error writing compare report to %s: %w", path, err)
+	}
+	return nil
+}
+
+// compareDbs compares db1 (the unwound db) against db2 (the reference
+// comparison db). By default it only compares per-table key counts; with
+// opts.deep it walks matching cursors on both dbs and diffs values too.
+func compareDbs(db1, db2 kv.RwDB, opts compareOptions) ([]string, *CompareReport, error) {
 	var discrepancies []string
 
+	report := &CompareReport{Mode: "count"}
+	if opts.deep {
+		report.Mode = "deep"
+	}
+
 	excludedTables := []string{
 		kv.Senders,
 	}
@@ -112,23 +257,304 @@ LOOP:
 				continue LOOP
 			}
 		}
+		if len(opts.tables) > 0 {
+			if _, ok := opts.tables[table]; !ok {
+				continue
+			}
+		}
+		report.Summary.TablesCompared++
+
+		if opts.deep {
+			diff, err := compareTableDeep(db1, db2, table, opts)
+			if err != nil {
+				return nil, nil, fmt.Errorf("error deep comparing table %s: %w", table, err)
+			}
+			if diff.Added+diff.Removed+diff.Changed == 0 {
+				continue
+			}
+			discrepancies = append(discrepancies, fmt.Sprintf("Table %s: %d added, %d removed, %d changed", table, diff.Added, diff.Removed, diff.Changed))
+			report.Tables = append(report.Tables, diff)
+			report.Summary.TablesDiffering++
+			report.Summary.Added += diff.Added
+			report.Summary.Removed += diff.Removed
+			report.Summary.Changed += diff.Changed
+			continue
+		}
 
 		count1, err := countKeysInDb(db1, table)
 		if err != nil {
-			return nil, fmt.Errorf("error counting keys in unwound db for table %s: %w", table, err)
+			return nil, nil, fmt.Errorf("error counting keys in unwound db for table %s: %w", table, err)
 		}
 
 		count2, err := countKeysInDb(db2, table)
 		if err != nil {
-			return nil, fmt.Errorf("error counting keys in comparison db for table %s: %w", table, err)
+			return nil, nil, fmt.Errorf("error counting keys in comparison db for table %s: %w", table, err)
 		}
 
 		if count1 != count2 {
 			discrepancies = append(discrepancies, fmt.Sprintf("Table %s: Unwound DB has %d entries, Comparison DB has %d entries", table, count1, count2))
+			report.Tables = append(report.Tables, &TableDiff{Table: table, CountOnly: true, Count1: count1, Count2: count2})
+			report.Summary.TablesDiffering++
 		}
 	}
 
-	return discrepancies, nil
+	return discrepancies, report, nil
+}
+
+// isDupSortTable reports whether table stores multiple values under one key
+// (MDBX DupSort, e.g. PlainState/HashedStorage), so a value-level diff has to
+// treat (key, value) as the comparison unit instead of pairing up entries
+// under the same key positionally.
+func isDupSortTable(table string) bool {
+	cfg, ok := kv.ChaindataTablesCfg[table]
+	return ok && cfg.Flags&kv.DupSort != 0
+}
+
+// compareTableDeep walks table on db1 and db2 with parallel cursors and
+// records added/removed/changed keys, bounded by opts.fromKey/opts.toKey and
+// capped at opts.maxDiffs sample diffs. DupSort tables are delegated to
+// compareTableDeepDupSort since they need a different comparison unit.
+func compareTableDeep(db1, db2 kv.RwDB, table string, opts compareOptions) (*TableDiff, error) {
+	tx1, err := db1.BeginRo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx1.Rollback()
+
+	tx2, err := db2.BeginRo(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer tx2.Rollback()
+
+	if isDupSortTable(table) {
+		return compareTableDeepDupSort(tx1, tx2, table, opts)
+	}
+
+	c1, err := tx1.Cursor(table)
+	if err != nil {
+		return nil, err
+	}
+	defer c1.Close()
+
+	c2, err := tx2.Cursor(table)
+	if err != nil {
+		return nil, err
+	}
+	defer c2.Close()
+
+	diff := &TableDiff{Table: table}
+
+	seek := func(c kv.Cursor) ([]byte, []byte, error) {
+		if len(opts.fromKey) > 0 {
+			return c.Seek(opts.fromKey)
+		}
+		return c.First()
+	}
+
+	k1, v1, err := seek(c1)
+	if err != nil {
+		return nil, err
+	}
+	k2, v2, err := seek(c2)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := func(k []byte) bool {
+		if k == nil {
+			return false
+		}
+		if len(opts.toKey) > 0 && bytes.Compare(k, opts.toKey) > 0 {
+			return false
+		}
+		return true
+	}
+
+	for inRange(k1) || inRange(k2) {
+		switch {
+		case !inRange(k1):
+			diff.Removed++
+			diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k2), Kind: "removed", NewValue: hex.EncodeToString(v2), Decoded: decodeTableValue(table, k2, v2)})
+			if k2, v2, err = c2.Next(); err != nil {
+				return nil, err
+			}
+		case !inRange(k2):
+			diff.Added++
+			diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k1), Kind: "added", OldValue: hex.EncodeToString(v1), Decoded: decodeTableValue(table, k1, v1)})
+			if k1, v1, err = c1.Next(); err != nil {
+				return nil, err
+			}
+		default:
+			switch bytes.Compare(k1, k2) {
+			case -1:
+				diff.Added++
+				diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k1), Kind: "added", OldValue: hex.EncodeToString(v1), Decoded: decodeTableValue(table, k1, v1)})
+				if k1, v1, err = c1.Next(); err != nil {
+					return nil, err
+				}
+			case 1:
+				diff.Removed++
+				diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k2), Kind: "removed", NewValue: hex.EncodeToString(v2), Decoded: decodeTableValue(table, k2, v2)})
+				if k2, v2, err = c2.Next(); err != nil {
+					return nil, err
+				}
+			default:
+				if !bytes.Equal(v1, v2) {
+					diff.Changed++
+					diff.addSample(opts.maxDiffs, KeyDiff{
+						Key:      hex.EncodeToString(k1),
+						Kind:     "changed",
+						OldValue: hex.EncodeToString(v1),
+						NewValue: hex.EncodeToString(v2),
+						Decoded:  decodeTableValue(table, k1, v2),
+					})
+				}
+				if k1, v1, err = c1.Next(); err != nil {
+					return nil, err
+				}
+				if k2, v2, err = c2.Next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// compareTableDeepDupSort is compareTableDeep's counterpart for DupSort
+// tables. A plain key-ordered Cursor.Next() pairs up db1/db2 entries
+// positionally, which misreports added/removed/changed the moment the two
+// DBs have a different number of values under the same key - exactly the
+// case this command exists to catch after a zkEVM UnwindToBatch. Walking
+// both sides with CursorDupSort and comparing (key, value) as the unit
+// avoids that: identical pairs match regardless of how many duplicates sit
+// either side of them, and only a genuinely missing/extra pair counts as a
+// diff.
+func compareTableDeepDupSort(tx1, tx2 kv.Tx, table string, opts compareOptions) (*TableDiff, error) {
+	c1, err := tx1.CursorDupSort(table)
+	if err != nil {
+		return nil, err
+	}
+	defer c1.Close()
+
+	c2, err := tx2.CursorDupSort(table)
+	if err != nil {
+		return nil, err
+	}
+	defer c2.Close()
+
+	diff := &TableDiff{Table: table}
+
+	seek := func(c kv.CursorDupSort) ([]byte, []byte, error) {
+		if len(opts.fromKey) > 0 {
+			return c.Seek(opts.fromKey)
+		}
+		return c.First()
+	}
+
+	k1, v1, err := seek(c1)
+	if err != nil {
+		return nil, err
+	}
+	k2, v2, err := seek(c2)
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := func(k []byte) bool {
+		if k == nil {
+			return false
+		}
+		if len(opts.toKey) > 0 && bytes.Compare(k, opts.toKey) > 0 {
+			return false
+		}
+		return true
+	}
+
+	// keyValueOrder orders two (key, value) pairs the way MDBX stores a
+	// DupSort table: primarily by key, then by value.
+	keyValueOrder := func(k1, v1, k2, v2 []byte) int {
+		if c := bytes.Compare(k1, k2); c != 0 {
+			return c
+		}
+		return bytes.Compare(v1, v2)
+	}
+
+	for inRange(k1) || inRange(k2) {
+		switch {
+		case !inRange(k1):
+			diff.Removed++
+			diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k2), Kind: "removed", NewValue: hex.EncodeToString(v2), Decoded: decodeTableValue(table, k2, v2)})
+			if k2, v2, err = c2.Next(); err != nil {
+				return nil, err
+			}
+		case !inRange(k2):
+			diff.Added++
+			diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k1), Kind: "added", OldValue: hex.EncodeToString(v1), Decoded: decodeTableValue(table, k1, v1)})
+			if k1, v1, err = c1.Next(); err != nil {
+				return nil, err
+			}
+		default:
+			switch keyValueOrder(k1, v1, k2, v2) {
+			case -1:
+				diff.Added++
+				diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k1), Kind: "added", OldValue: hex.EncodeToString(v1), Decoded: decodeTableValue(table, k1, v1)})
+				if k1, v1, err = c1.Next(); err != nil {
+					return nil, err
+				}
+			case 1:
+				diff.Removed++
+				diff.addSample(opts.maxDiffs, KeyDiff{Key: hex.EncodeToString(k2), Kind: "removed", NewValue: hex.EncodeToString(v2), Decoded: decodeTableValue(table, k2, v2)})
+				if k2, v2, err = c2.Next(); err != nil {
+					return nil, err
+				}
+			default:
+				// identical (key, value) pair on both sides - not a diff
+				if k1, v1, err = c1.Next(); err != nil {
+					return nil, err
+				}
+				if k2, v2, err = c2.Next(); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return diff, nil
+}
+
+// decodeTableValue best-effort decodes account entries so diffs against
+// zkEVM state after an UnwindToBatch are readable without a hex dump. Tables
+// it doesn't know how to decode return an empty string.
+func decodeTableValue(table string, k, v []byte) (decoded string) {
+	// DecodeForStorage can index past the end of a short or malformed value;
+	// treat that like any other undecodable value instead of crashing the walk
+	defer func() {
+		if recover() != nil {
+			decoded = ""
+		}
+	}()
+
+	switch table {
+	case kv.PlainState:
+		if len(k) != 20 || len(v) == 0 {
+			return ""
+		}
+	case kv.HashedAccounts:
+		if len(v) == 0 {
+			return ""
+		}
+	default:
+		return ""
+	}
+
+	var acc accounts.Account
+	if err := acc.DecodeForStorage(v); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("account{nonce=%d balance=%s incarnation=%d codeHash=%x}", acc.Nonce, acc.Balance.String(), acc.Incarnation, acc.CodeHash)
 }
 
 func countKeysInDb(db kv.RwDB, table string) (uint64, error) {
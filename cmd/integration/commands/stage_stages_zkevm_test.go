@@ -0,0 +1,88 @@
+package commands
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/gateway-fm/cdk-erigon-lib/kv"
+	"github.com/gateway-fm/cdk-erigon-lib/kv/memdb"
+	"github.com/ledgerwatch/erigon/core/types/accounts"
+)
+
+func mustPut(t *testing.T, db kv.RwDB, table string, k, v []byte) {
+	t.Helper()
+	if err := db.Update(context.Background(), func(tx kv.RwTx) error {
+		return tx.Put(table, k, v)
+	}); err != nil {
+		t.Fatalf("put %s: %v", table, err)
+	}
+}
+
+// TestCompareTableDeepDupSort guards against the positional-pairing bug: a
+// key with a different number of duplicates on each side must be reported as
+// an added/removed duplicate, not misread as a single "changed" value.
+func TestCompareTableDeepDupSort(t *testing.T) {
+	db1 := memdb.NewTestDB(t)
+	db2 := memdb.NewTestDB(t)
+
+	key := []byte{0x01}
+	mustPut(t, db1, kv.PlainState, key, []byte{0x0a})
+	mustPut(t, db1, kv.PlainState, key, []byte{0x0b})
+	mustPut(t, db2, kv.PlainState, key, []byte{0x0a})
+
+	diff, err := compareTableDeep(db1, db2, kv.PlainState, compareOptions{deep: true})
+	if err != nil {
+		t.Fatalf("compareTableDeep: %v", err)
+	}
+	if diff.Added != 1 || diff.Removed != 0 || diff.Changed != 0 {
+		t.Fatalf("got added=%d removed=%d changed=%d, want added=1 removed=0 changed=0", diff.Added, diff.Removed, diff.Changed)
+	}
+}
+
+// TestCompareTableDeepDupSortDecodesPlainState guards against decodeTableValue
+// being dead code for PlainState: PlainState is a DupSort table, so its
+// samples are built by compareTableDeepDupSort, not the plain-cursor path -
+// the Decoded field must still be populated there.
+func TestCompareTableDeepDupSortDecodesPlainState(t *testing.T) {
+	db1 := memdb.NewTestDB(t)
+	db2 := memdb.NewTestDB(t)
+
+	key := make([]byte, 20)
+	key[19] = 0x01
+
+	acc := accounts.Account{Nonce: 7, Balance: *big.NewInt(100)}
+	v := make([]byte, acc.EncodingLengthForStorage())
+	acc.EncodeForStorage(v)
+	mustPut(t, db1, kv.PlainState, key, v)
+
+	diff, err := compareTableDeep(db1, db2, kv.PlainState, compareOptions{deep: true})
+	if err != nil {
+		t.Fatalf("compareTableDeep: %v", err)
+	}
+	if diff.Added != 1 {
+		t.Fatalf("got added=%d, want 1", diff.Added)
+	}
+	if len(diff.SampleDiffs) != 1 || diff.SampleDiffs[0].Decoded == "" {
+		t.Fatalf("expected a decoded PlainState sample, got %+v", diff.SampleDiffs)
+	}
+}
+
+// TestCompareTableDeepChanged exercises the plain (non-DupSort) path: same
+// key, different value, counts as one "changed" entry.
+func TestCompareTableDeepChanged(t *testing.T) {
+	db1 := memdb.NewTestDB(t)
+	db2 := memdb.NewTestDB(t)
+
+	key := []byte{0xaa, 0xbb}
+	mustPut(t, db1, kv.HashedAccounts, key, []byte{0x01})
+	mustPut(t, db2, kv.HashedAccounts, key, []byte{0x02})
+
+	diff, err := compareTableDeep(db1, db2, kv.HashedAccounts, compareOptions{deep: true})
+	if err != nil {
+		t.Fatalf("compareTableDeep: %v", err)
+	}
+	if diff.Added != 0 || diff.Removed != 0 || diff.Changed != 1 {
+		t.Fatalf("got added=%d removed=%d changed=%d, want added=0 removed=0 changed=1", diff.Added, diff.Removed, diff.Changed)
+	}
+}
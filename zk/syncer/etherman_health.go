@@ -0,0 +1,237 @@
+package syncer
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gateway-fm/cdk-erigon-lib/metrics"
+)
+
+// ethermanState is the circuit-breaker state of a single IEtherman endpoint.
+type ethermanState int
+
+const (
+	ethermanHealthy ethermanState = iota
+	ethermanDegraded
+	ethermanTripped
+)
+
+func (s ethermanState) String() string {
+	switch s {
+	case ethermanHealthy:
+		return "healthy"
+	case ethermanDegraded:
+		return "degraded"
+	case ethermanTripped:
+		return "tripped"
+	default:
+		return "unknown"
+	}
+}
+
+const (
+	// degradeConsecutiveFailures moves an endpoint out of the preferred pool,
+	// tripConsecutiveFailures takes it out of rotation entirely.
+	degradeConsecutiveFailures = 3
+	tripConsecutiveFailures    = 8
+
+	// cooldown before a tripped endpoint is re-probed, doubling on every
+	// further failed probe up to maxTripCooldown.
+	baseTripCooldown = 5 * time.Second
+	maxTripCooldown  = 5 * time.Minute
+
+	healthProbeInterval = 15 * time.Second
+
+	// minSafeBlockRange is the floor a learned safe range is never shrunk below.
+	minSafeBlockRange = 16
+	// rangeGrowthStreak is how many consecutive successes at the current
+	// learned range are required before it's allowed to grow again.
+	rangeGrowthStreak = 5
+)
+
+// EndpointStats is a point-in-time snapshot of a single L1 endpoint's health,
+// returned by L1Syncer.EndpointStats() for operators to inspect.
+type EndpointStats struct {
+	Label               string
+	SuccessCount        uint64
+	FailureCount        uint64
+	ConsecutiveFailures uint64
+	AvgLatency          time.Duration
+	LastErrorAt         time.Time
+	State               string
+}
+
+// ethermanStats tracks the recent behaviour of a single IEtherman endpoint so
+// the syncer can route around flaky ones instead of round-robining through
+// them blindly.
+type ethermanStats struct {
+	mtx sync.Mutex
+
+	label string
+
+	successCount        uint64
+	failureCount        uint64
+	consecutiveFailures uint64
+	avgLatency          time.Duration
+	lastErrorAt         time.Time
+	state               ethermanState
+	trippedAt           time.Time
+	tripCooldown        time.Duration
+
+	// safeRange is this endpoint's learned eth_getLogs block range: 0 means
+	// "no limit observed yet, use the configured default". It shrinks on size
+	// errors and grows back on sustained success.
+	safeRange          uint64
+	rangeSuccessStreak int
+
+	callsTotal   *metrics.Counter
+	errorsTotal  *metrics.Counter
+	latencyGauge *metrics.Gauge
+	stateGauge   *metrics.Gauge
+}
+
+func newEthermanStats(label string) *ethermanStats {
+	return &ethermanStats{
+		label:        label,
+		state:        ethermanHealthy,
+		tripCooldown: baseTripCooldown,
+		callsTotal:   metrics.GetOrCreateCounter(fmt.Sprintf(`zk_l1_etherman_calls_total{endpoint="%s"}`, label)),
+		errorsTotal:  metrics.GetOrCreateCounter(fmt.Sprintf(`zk_l1_etherman_errors_total{endpoint="%s"}`, label)),
+		latencyGauge: metrics.GetOrCreateGauge(fmt.Sprintf(`zk_l1_etherman_avg_latency_ms{endpoint="%s"}`, label), nil),
+		stateGauge:   metrics.GetOrCreateGauge(fmt.Sprintf(`zk_l1_etherman_state{endpoint="%s"}`, label), nil),
+	}
+}
+
+func (e *ethermanStats) recordSuccess(latency time.Duration) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.successCount++
+	e.consecutiveFailures = 0
+
+	if e.avgLatency == 0 {
+		e.avgLatency = latency
+	} else {
+		// light EWMA so a handful of slow calls don't dominate the average
+		e.avgLatency = (e.avgLatency*4 + latency) / 5
+	}
+
+	e.callsTotal.Inc()
+	e.latencyGauge.Set(float64(e.avgLatency.Milliseconds()))
+
+	if e.state != ethermanHealthy {
+		e.state = ethermanHealthy
+		e.stateGauge.Set(float64(e.state))
+	}
+}
+
+func (e *ethermanStats) recordFailure(err error) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.failureCount++
+	e.consecutiveFailures++
+	e.lastErrorAt = time.Now()
+
+	e.callsTotal.Inc()
+	e.errorsTotal.Inc()
+	_ = err // recorded via lastErrorAt/state; kept for future structured logging
+
+	switch {
+	case e.consecutiveFailures >= tripConsecutiveFailures:
+		if e.state == ethermanTripped {
+			// still failing after a probe - back off further
+			e.tripCooldown *= 2
+			if e.tripCooldown > maxTripCooldown {
+				e.tripCooldown = maxTripCooldown
+			}
+		} else {
+			e.tripCooldown = baseTripCooldown
+		}
+		e.state = ethermanTripped
+		e.trippedAt = time.Now()
+	case e.consecutiveFailures >= degradeConsecutiveFailures:
+		e.state = ethermanDegraded
+	}
+
+	e.stateGauge.Set(float64(e.state))
+}
+
+// isTrippedAndReady reports whether this endpoint is tripped but its cooldown
+// has elapsed, making it a candidate for a health probe or a last-resort pick.
+func (e *ethermanStats) isTrippedAndReady() bool {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return e.state == ethermanTripped && time.Since(e.trippedAt) >= e.tripCooldown
+}
+
+// currentSafeRange returns this endpoint's learned safe eth_getLogs range, or
+// configuredMax if nothing has been learned yet.
+func (e *ethermanStats) currentSafeRange(configuredMax uint64) uint64 {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.safeRange == 0 {
+		return configuredMax
+	}
+	return e.safeRange
+}
+
+// recordRangeSuccess counts a successful fetch of the given size towards
+// growing the learned safe range back up, once we've seen enough of them in a
+// row to be confident it wasn't a fluke.
+func (e *ethermanStats) recordRangeSuccess(size, configuredMax uint64) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	if e.safeRange == 0 || e.safeRange >= configuredMax {
+		return
+	}
+
+	e.rangeSuccessStreak++
+	if e.rangeSuccessStreak < rangeGrowthStreak {
+		return
+	}
+	e.rangeSuccessStreak = 0
+
+	grown := e.safeRange + e.safeRange/2
+	if grown > configuredMax {
+		grown = configuredMax
+	}
+	e.safeRange = grown
+}
+
+// recordRangeFailure halves the learned safe range after a size-class error
+// fetching a range of the given size, and returns the new range.
+func (e *ethermanStats) recordRangeFailure(size uint64) uint64 {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	e.rangeSuccessStreak = 0
+
+	basis := e.safeRange
+	if basis == 0 || basis > size {
+		basis = size
+	}
+	shrunk := basis / 2
+	if shrunk < minSafeBlockRange {
+		shrunk = minSafeBlockRange
+	}
+	e.safeRange = shrunk
+	return shrunk
+}
+
+func (e *ethermanStats) snapshot() EndpointStats {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	return EndpointStats{
+		Label:               e.label,
+		SuccessCount:        e.successCount,
+		FailureCount:        e.failureCount,
+		ConsecutiveFailures: e.consecutiveFailures,
+		AvgLatency:          e.avgLatency,
+		LastErrorAt:         e.lastErrorAt,
+		State:               e.state.String(),
+	}
+}
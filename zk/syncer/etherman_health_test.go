@@ -0,0 +1,88 @@
+package syncer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEthermanStatsTripsAfterConsecutiveFailures(t *testing.T) {
+	st := newEthermanStats(t.Name())
+
+	for i := 0; i < tripConsecutiveFailures-1; i++ {
+		st.recordFailure(errors.New("boom"))
+		if st.state == ethermanTripped {
+			t.Fatalf("tripped too early, after %d failures", i+1)
+		}
+	}
+
+	st.recordFailure(errors.New("boom"))
+	if st.state != ethermanTripped {
+		t.Fatalf("got state %s, want tripped after %d consecutive failures", st.state, tripConsecutiveFailures)
+	}
+}
+
+func TestEthermanStatsRecoversOnSuccess(t *testing.T) {
+	st := newEthermanStats(t.Name())
+
+	for i := 0; i < tripConsecutiveFailures; i++ {
+		st.recordFailure(errors.New("boom"))
+	}
+	if st.state != ethermanTripped {
+		t.Fatalf("expected tripped, got %s", st.state)
+	}
+
+	st.recordSuccess(10 * time.Millisecond)
+	if st.state != ethermanHealthy {
+		t.Fatalf("got state %s, want healthy after a success", st.state)
+	}
+	if st.consecutiveFailures != 0 {
+		t.Fatalf("got consecutiveFailures %d, want 0 after a success", st.consecutiveFailures)
+	}
+}
+
+func TestEthermanStatsSafeRangeShrinksAndGrows(t *testing.T) {
+	st := newEthermanStats(t.Name())
+
+	const configuredMax = 1000
+
+	if got := st.currentSafeRange(configuredMax); got != configuredMax {
+		t.Fatalf("got %d, want configuredMax %d before any failure", got, configuredMax)
+	}
+
+	shrunk := st.recordRangeFailure(configuredMax)
+	if shrunk != configuredMax/2 {
+		t.Fatalf("got shrunk range %d, want %d", shrunk, configuredMax/2)
+	}
+
+	shrunk = st.recordRangeFailure(shrunk)
+	if shrunk < minSafeBlockRange {
+		t.Fatalf("shrunk range %d fell below minSafeBlockRange %d", shrunk, minSafeBlockRange)
+	}
+
+	for i := 0; i < rangeGrowthStreak; i++ {
+		st.recordRangeSuccess(shrunk, configuredMax)
+	}
+	if got := st.currentSafeRange(configuredMax); got <= shrunk {
+		t.Fatalf("got safe range %d, want it to grow past %d after %d successes", got, shrunk, rangeGrowthStreak)
+	}
+}
+
+func TestEthermanStatsIsTrippedAndReady(t *testing.T) {
+	st := newEthermanStats(t.Name())
+	for i := 0; i < tripConsecutiveFailures; i++ {
+		st.recordFailure(errors.New("boom"))
+	}
+
+	if st.isTrippedAndReady() {
+		t.Fatal("expected not ready immediately after tripping, cooldown hasn't elapsed")
+	}
+
+	st.mtx.Lock()
+	st.trippedAt = time.Now().Add(-2 * baseTripCooldown)
+	st.mtx.Unlock()
+
+	if !st.isTrippedAndReady() {
+		t.Fatal("expected ready once the cooldown has elapsed")
+	}
+}
@@ -0,0 +1,233 @@
+package syncer
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+
+	"github.com/gateway-fm/cdk-erigon-lib/common"
+	"github.com/gateway-fm/cdk-erigon-lib/kv"
+	ethTypes "github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rlp"
+)
+
+// L1LogCache and L1BlockHeaderCache let a restarted node skip re-fetching L1
+// history it already has. L1LogCache is keyed by blockNumber||logIndex so a
+// block's entries sort together and can be range-pruned on reorg;
+// L1BlockHeaderCache is keyed by blockNumber alone.
+const (
+	L1LogCache         = "L1LogCache"
+	L1BlockHeaderCache = "L1BlockHeaderCache"
+)
+
+// L1CacheTables lists the tables the cache needs registered in the chaindata schema.
+var L1CacheTables = []string{L1LogCache, L1BlockHeaderCache}
+
+func init() {
+	for _, table := range L1CacheTables {
+		kv.ChaindataTablesCfg[table] = kv.TableCfgItem{}
+	}
+}
+
+// noLogsSentinel marks a block as scanned with zero matching logs, so a
+// sparse range can still be served entirely from cache without refetching to
+// confirm "no logs here" each time.
+const noLogsSentinel = math.MaxUint32
+
+// L1Cache is a thin MDBX-backed cache in front of L1Syncer's network calls.
+// Only entries for blocks at or below the last finalized/confirmed L1 block
+// are trustworthy to read back, since younger ones could still be reorged out
+// before they're confirmed; GetLogsForRange/GetBlock take that boundary
+// explicitly rather than guessing it.
+type L1Cache struct {
+	db kv.RwDB
+}
+
+func NewL1Cache(db kv.RwDB) *L1Cache {
+	return &L1Cache{db: db}
+}
+
+func logCacheKey(blockNumber uint64, logIndex uint) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key[:8], blockNumber)
+	binary.BigEndian.PutUint32(key[8:], uint32(logIndex))
+	return key
+}
+
+func blockCacheKey(blockNumber uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, blockNumber)
+	return key
+}
+
+// GetLogsForRange returns the cached logs for [from, to] if every block in
+// that range has been fully cached (either real logs or a noLogsSentinel
+// marker) and the whole range is at or below boundary. ok is false if the
+// cache can't fully answer the query and the caller should hit the network.
+func (c *L1Cache) GetLogsForRange(from, to, boundary uint64) (logs []ethTypes.Log, ok bool, err error) {
+	if to > boundary {
+		return nil, false, nil
+	}
+
+	err = c.db.View(context.Background(), func(tx kv.Tx) error {
+		cursor, err := tx.Cursor(L1LogCache)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close()
+
+		blockNumber := from
+		k, v, err := cursor.Seek(logCacheKey(from, 0))
+		for {
+			if err != nil {
+				return err
+			}
+			if k == nil || binary.BigEndian.Uint64(k[:8]) > blockNumber {
+				// nothing cached for blockNumber - range isn't fully covered
+				ok = false
+				return nil
+			}
+
+			for binary.BigEndian.Uint64(k[:8]) == blockNumber {
+				logIndex := binary.BigEndian.Uint32(k[8:])
+				if logIndex != noLogsSentinel {
+					var l ethTypes.Log
+					if err := json.Unmarshal(v, &l); err != nil {
+						return err
+					}
+					logs = append(logs, l)
+				}
+				k, v, err = cursor.Next()
+				if err != nil {
+					return err
+				}
+				if k == nil {
+					break
+				}
+			}
+
+			if blockNumber == to {
+				ok = true
+				return nil
+			}
+			blockNumber++
+		}
+	})
+
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return logs, true, nil
+}
+
+// CacheLogsForRange stores logs fetched for [from, to], plus a sentinel for
+// any block in the range that had no matching logs, so future reads of the
+// same range can be served entirely from cache. It's a no-op if any block in
+// the range is above boundary: caching a block before it's finalized/confirmed
+// would let a later reorg of that block go unnoticed, since GetLogsForRange
+// would then serve the stale, no-longer-canonical logs back as if immutable.
+func (c *L1Cache) CacheLogsForRange(from, to, boundary uint64, logs []ethTypes.Log) error {
+	if to > boundary {
+		return nil
+	}
+	return c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		seen := make(map[uint64]bool, len(logs))
+		for _, l := range logs {
+			// JSON keeps the block/tx metadata that a Log's RLP encoding drops;
+			// its decoder rejects a null topics list, so store nil as empty
+			if l.Topics == nil {
+				l.Topics = []common.Hash{}
+			}
+			v, err := json.Marshal(l)
+			if err != nil {
+				return err
+			}
+			if err := tx.Put(L1LogCache, logCacheKey(l.BlockNumber, l.Index), v); err != nil {
+				return err
+			}
+			seen[l.BlockNumber] = true
+		}
+
+		for bn := from; bn <= to; bn++ {
+			if seen[bn] {
+				continue
+			}
+			if err := tx.Put(L1LogCache, logCacheKey(bn, noLogsSentinel), nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetBlock returns the cached header-only block for number if it's cached and
+// at or below boundary.
+func (c *L1Cache) GetBlock(number, boundary uint64) (*ethTypes.Block, bool, error) {
+	if number > boundary {
+		return nil, false, nil
+	}
+
+	var block *ethTypes.Block
+	err := c.db.View(context.Background(), func(tx kv.Tx) error {
+		v, err := tx.GetOne(L1BlockHeaderCache, blockCacheKey(number))
+		if err != nil || v == nil {
+			return err
+		}
+		var header ethTypes.Header
+		if err := rlp.DecodeBytes(v, &header); err != nil {
+			return err
+		}
+		block = ethTypes.NewBlockWithHeader(&header)
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return block, block != nil, nil
+}
+
+// CacheBlock stores block's header, keyed by block number. It's a no-op if
+// block is above boundary, for the same reason CacheLogsForRange is: a block
+// cached before it's finalized/confirmed could later be reorged out without
+// GetBlock ever noticing.
+func (c *L1Cache) CacheBlock(block *ethTypes.Block, boundary uint64) error {
+	if block.NumberU64() > boundary {
+		return nil
+	}
+	v, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	return c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		return tx.Put(L1BlockHeaderCache, blockCacheKey(block.NumberU64()), v)
+	})
+}
+
+// Prune drops every cached log and header at or above belowBlock, e.g. after
+// a reorg invalidates that range.
+func (c *L1Cache) Prune(belowBlock uint64) error {
+	return c.db.Update(context.Background(), func(tx kv.RwTx) error {
+		if err := pruneCacheTableFrom(tx, L1LogCache, belowBlock); err != nil {
+			return err
+		}
+		return pruneCacheTableFrom(tx, L1BlockHeaderCache, belowBlock)
+	})
+}
+
+func pruneCacheTableFrom(tx kv.RwTx, table string, belowBlock uint64) error {
+	cursor, err := tx.RwCursor(table)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	k, _, err := cursor.Seek(blockCacheKey(belowBlock))
+	for err == nil && k != nil {
+		if err = cursor.DeleteCurrent(); err != nil {
+			return err
+		}
+		k, _, err = cursor.Next()
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package syncer
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/gateway-fm/cdk-erigon-lib/kv"
+	"github.com/gateway-fm/cdk-erigon-lib/kv/mdbx"
+	ethTypes "github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/log/v3"
+)
+
+func newTestL1Cache(t *testing.T) *L1Cache {
+	t.Helper()
+	db := mdbx.NewMDBX(log.New()).InMem(t.TempDir()).WithTableCfg(func(kv.TableCfg) kv.TableCfg {
+		cfg := kv.TableCfg{}
+		for _, table := range L1CacheTables {
+			cfg[table] = kv.TableCfgItem{}
+		}
+		return cfg
+	}).MustOpen()
+	t.Cleanup(db.Close)
+	return NewL1Cache(db)
+}
+
+func TestL1CacheLogsRoundTrip(t *testing.T) {
+	c := newTestL1Cache(t)
+
+	logs := []ethTypes.Log{
+		{BlockNumber: 10, Index: 0},
+		{BlockNumber: 10, Index: 1},
+		{BlockNumber: 12, Index: 0},
+	}
+	if err := c.CacheLogsForRange(10, 12, 100, logs); err != nil {
+		t.Fatalf("CacheLogsForRange: %v", err)
+	}
+
+	got, ok, err := c.GetLogsForRange(10, 12, 100)
+	if err != nil {
+		t.Fatalf("GetLogsForRange: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected the cache to fully cover [10,12]")
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d logs, want 3", len(got))
+	}
+}
+
+func TestL1CacheLogsNotFullyCached(t *testing.T) {
+	c := newTestL1Cache(t)
+
+	if err := c.CacheLogsForRange(10, 10, 100, nil); err != nil {
+		t.Fatalf("CacheLogsForRange: %v", err)
+	}
+
+	// block 11 was never cached, so [10,12] can't be served entirely from cache
+	if _, ok, err := c.GetLogsForRange(10, 12, 100); err != nil {
+		t.Fatalf("GetLogsForRange: %v", err)
+	} else if ok {
+		t.Fatal("expected ok=false for a partially cached range")
+	}
+}
+
+// TestL1CacheWritesGatedByBoundary guards the fix for writes ignoring the
+// finality boundary: a write for a block above boundary must be a no-op, the
+// same way reads already refuse to serve it.
+func TestL1CacheWritesGatedByBoundary(t *testing.T) {
+	c := newTestL1Cache(t)
+
+	logs := []ethTypes.Log{{BlockNumber: 50, Index: 0}}
+	if err := c.CacheLogsForRange(50, 50, 10, logs); err != nil {
+		t.Fatalf("CacheLogsForRange: %v", err)
+	}
+	if _, ok, err := c.GetLogsForRange(50, 50, 100); err != nil {
+		t.Fatalf("GetLogsForRange: %v", err)
+	} else if ok {
+		t.Fatal("expected the log write above boundary to have been skipped")
+	}
+
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(50)})
+	if err := c.CacheBlock(block, 10); err != nil {
+		t.Fatalf("CacheBlock: %v", err)
+	}
+	if _, ok, err := c.GetBlock(50, 100); err != nil {
+		t.Fatalf("GetBlock: %v", err)
+	} else if ok {
+		t.Fatal("expected the block write above boundary to have been skipped")
+	}
+}
+
+func TestL1CachePruneRemovesInvalidatedRange(t *testing.T) {
+	c := newTestL1Cache(t)
+
+	logs := []ethTypes.Log{{BlockNumber: 5, Index: 0}}
+	if err := c.CacheLogsForRange(5, 5, 100, logs); err != nil {
+		t.Fatalf("CacheLogsForRange: %v", err)
+	}
+	block := ethTypes.NewBlockWithHeader(&ethTypes.Header{Number: big.NewInt(5)})
+	if err := c.CacheBlock(block, 100); err != nil {
+		t.Fatalf("CacheBlock: %v", err)
+	}
+
+	if err := c.Prune(5); err != nil {
+		t.Fatalf("Prune: %v", err)
+	}
+
+	if _, ok, err := c.GetLogsForRange(5, 5, 100); err != nil {
+		t.Fatalf("GetLogsForRange: %v", err)
+	} else if ok {
+		t.Fatal("expected the pruned range to no longer be cached")
+	}
+	if _, ok, err := c.GetBlock(5, 100); err != nil {
+		t.Fatalf("GetBlock: %v", err)
+	} else if ok {
+		t.Fatal("expected the pruned block to no longer be cached")
+	}
+}
@@ -0,0 +1,92 @@
+package syncer
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how many times and how long the syncer waits between
+// retries of a failed eth_getLogs call, replacing the previous hardcoded
+// "retry > 5" check so upstream configuration can tune it per deployment.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      time.Duration
+}
+
+// DefaultRetryPolicy keeps the syncer's previous hardcoded limits, up to 5
+// retries backing off by 2s per attempt, and adds up to 1s of random jitter
+// per retry with the delay capped at 60s.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   2 * time.Second,
+		MaxDelay:    60 * time.Second,
+		Jitter:      time.Second,
+	}
+}
+
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay * time.Duration(attempt)
+	if d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if p.Jitter > 0 {
+		d += time.Duration(rand.Int63n(int64(p.Jitter)))
+	}
+	return d
+}
+
+// logsErrorClass buckets the error strings different L1 RPC providers return
+// for eth_getLogs so the syncer can react appropriately instead of treating
+// every failure the same way.
+type logsErrorClass int
+
+const (
+	logsErrorUnknown logsErrorClass = iota
+	logsErrorTooManyResults
+	logsErrorResponseTooLarge
+	logsErrorRangeTooLarge
+	logsErrorRateLimited
+	logsErrorServer
+)
+
+// isSizeError reports whether the provider rejected the request because the
+// requested range/response was too big, meaning a smaller range is likely to
+// succeed.
+func (c logsErrorClass) isSizeError() bool {
+	switch c {
+	case logsErrorTooManyResults, logsErrorResponseTooLarge, logsErrorRangeTooLarge:
+		return true
+	default:
+		return false
+	}
+}
+
+// classifyLogsError pattern-matches on the common provider error strings for
+// eth_getLogs. Providers don't agree on error codes here, only on roughly
+// these phrases, so this is necessarily a best-effort classification.
+func classifyLogsError(err error) logsErrorClass {
+	if err == nil {
+		return logsErrorUnknown
+	}
+
+	msg := strings.ToLower(err.Error())
+
+	switch {
+	case strings.Contains(msg, "query returned more than") && strings.Contains(msg, "result"):
+		return logsErrorTooManyResults
+	case strings.Contains(msg, "response too large") || strings.Contains(msg, "response size exceeded") || strings.Contains(msg, "limit exceeded"):
+		return logsErrorResponseTooLarge
+	case strings.Contains(msg, "exceeds the max block range") || strings.Contains(msg, "block range") || strings.Contains(msg, "too many blocks") || strings.Contains(msg, "range is too large"):
+		return logsErrorRangeTooLarge
+	case strings.Contains(msg, "429") || strings.Contains(msg, "too many requests") || strings.Contains(msg, "rate limit"):
+		return logsErrorRateLimited
+	case strings.Contains(msg, "502") || strings.Contains(msg, "503") || strings.Contains(msg, "504") || strings.Contains(msg, "internal server error") || strings.Contains(msg, "bad gateway"):
+		return logsErrorServer
+	default:
+		return logsErrorUnknown
+	}
+}
@@ -0,0 +1,74 @@
+package syncer
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestClassifyLogsError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want logsErrorClass
+	}{
+		{"nil", nil, logsErrorUnknown},
+		{"too many results", errors.New("query returned more than 10000 results"), logsErrorTooManyResults},
+		{"response too large", errors.New("response too large"), logsErrorResponseTooLarge},
+		{"limit exceeded", errors.New("limit exceeded for this request"), logsErrorResponseTooLarge},
+		{"block range", errors.New("exceeds the max block range 5000"), logsErrorRangeTooLarge},
+		{"too many blocks", errors.New("too many blocks requested"), logsErrorRangeTooLarge},
+		{"rate limited", errors.New("429 Too Many Requests"), logsErrorRateLimited},
+		{"bad gateway", errors.New("502 bad gateway"), logsErrorServer},
+		{"unrecognised", errors.New("connection refused"), logsErrorUnknown},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := classifyLogsError(c.err); got != c.want {
+				t.Fatalf("classifyLogsError(%v) = %v, want %v", c.err, got, c.want)
+			}
+		})
+	}
+}
+
+func TestLogsErrorClassIsSizeError(t *testing.T) {
+	sizeClasses := map[logsErrorClass]bool{
+		logsErrorTooManyResults:   true,
+		logsErrorResponseTooLarge: true,
+		logsErrorRangeTooLarge:    true,
+		logsErrorRateLimited:      false,
+		logsErrorServer:           false,
+		logsErrorUnknown:          false,
+	}
+	for class, want := range sizeClasses {
+		if got := class.isSizeError(); got != want {
+			t.Fatalf("logsErrorClass(%v).isSizeError() = %v, want %v", class, got, want)
+		}
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 5 * time.Second, Jitter: 0}
+
+	if got := p.delay(1); got != time.Second {
+		t.Fatalf("delay(1) = %v, want %v", got, time.Second)
+	}
+	if got := p.delay(3); got != 3*time.Second {
+		t.Fatalf("delay(3) = %v, want %v", got, 3*time.Second)
+	}
+	if got := p.delay(10); got != p.MaxDelay {
+		t.Fatalf("delay(10) = %v, want capped at MaxDelay %v", got, p.MaxDelay)
+	}
+}
+
+func TestRetryPolicyDelayJitterStaysBounded(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second, Jitter: 500 * time.Millisecond}
+
+	for i := 0; i < 50; i++ {
+		d := p.delay(1)
+		if d < time.Second || d >= time.Second+500*time.Millisecond {
+			t.Fatalf("delay(1) = %v, want within [1s, 1.5s)", d)
+		}
+	}
+}
@@ -2,8 +2,11 @@ package syncer
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
+	"math/rand"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -28,16 +31,45 @@ var errorShortResponseLT96 = fmt.Errorf("response too short to contain last batc
 
 const rollupSequencedBatchesSignature = "0x25280169" // hardcoded abi signature
 
+// defaultL1BlockHashWindow is how many of the most recently seen L1 blocks we
+// keep hashes for when no explicit window size is configured.
+const defaultL1BlockHashWindow = 128
+
+// L1BlockHash is a single entry in the rolling window used to detect L1 reorgs.
+type L1BlockHash struct {
+	Number uint64
+	Hash   common.Hash
+}
+
+// L1ReorgEvent describes an L1 reorg detected by checkForReorg. FromBlock is
+// the first invalidated block (the one after the common ancestor, or the
+// oldest block in the hash window when the reorg goes deeper than the window)
+// and ToBlock is the last invalidated block, both inclusive. OldHashes/NewHashes are in
+// ascending block order and line up index-for-index.
+type L1ReorgEvent struct {
+	FromBlock uint64
+	ToBlock   uint64
+	OldHashes []common.Hash
+	NewHashes []common.Hash
+}
+
 type IEtherman interface {
 	BlockByNumber(ctx context.Context, blockNumber *big.Int) (*ethTypes.Block, error)
 	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethTypes.Log, error)
 	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 	TransactionByHash(ctx context.Context, hash common.Hash) (ethTypes.Transaction, bool, error)
+
+	// SubscribeFilterLogs and SubscribeNewHead are optional: implementations
+	// that can't push notifications should return rpc.ErrNotificationsUnsupported
+	// so L1Syncer falls back to polling FilterLogs/BlockByNumber.
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error)
+	SubscribeNewHead(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error)
 }
 
 type fetchJob struct {
-	From uint64
-	To   uint64
+	From    uint64
+	To      uint64
+	Attempt int
 }
 
 type jobResult struct {
@@ -50,13 +82,35 @@ type L1Syncer struct {
 	etherMans            []IEtherman
 	ethermanIndex        uint8
 	ethermanMtx          *sync.Mutex
+	ethermanHealth       []*ethermanStats
 	l1ContractAddresses  []common.Address
 	topics               [][]common.Hash
 	blockRange           uint64
 	queryDelay           uint64
 	l1QueryBlocksThreads uint64
+	retryPolicy          RetryPolicy
+
+	// confirmationDepth, when non-zero, makes the syncer poll at
+	// latest-confirmationDepth instead of the node's "finalized" tag, for L1
+	// endpoints that don't serve rpc.FinalizedBlockNumber.
+	confirmationDepth uint64
+
+	// useSubscriptions switches Run to eth_subscribe("logs"/"newHeads")
+	// streaming instead of polling FilterLogs on a timer, falling back to
+	// polling if the endpoint doesn't support it or a subscription drops.
+	useSubscriptions bool
 
-	latestL1Block uint64
+	// cache is an optional persistent log/header cache; nil means no caching.
+	cache *L1Cache
+
+	latestL1Block     uint64
+	latestL1BlockHash common.Hash
+
+	// hashWindowSize is how many of the most recently seen L1 blocks are kept
+	// in hashWindow to detect reorgs against.
+	hashWindowSize uint64
+	hashWindowMtx  sync.Mutex
+	hashWindow     []L1BlockHash // ascending by block number, tip last
 
 	// atomic
 	isSyncStarted      atomic.Bool
@@ -66,35 +120,167 @@ type L1Syncer struct {
 	// Channels
 	logsChan            chan []ethTypes.Log
 	progressMessageChan chan string
+	reorgChan           chan L1ReorgEvent
 }
 
-func NewL1Syncer(etherMans []IEtherman, l1ContractAddresses []common.Address, topics [][]common.Hash, blockRange, queryDelay, l1QueryBlocksThreads uint64) *L1Syncer {
+// NewL1Syncer builds a syncer. hashWindowSize is how many L1 blocks of hash
+// history to keep for reorg detection (0 defaults to defaultL1BlockHashWindow).
+// confirmationDepth is 0 to poll at the node's finalized tag, or a positive
+// number of blocks behind the tip for endpoints without a finalized tag.
+func NewL1Syncer(etherMans []IEtherman, l1ContractAddresses []common.Address, topics [][]common.Hash, blockRange, queryDelay, l1QueryBlocksThreads, hashWindowSize, confirmationDepth uint64) *L1Syncer {
+	if hashWindowSize == 0 {
+		hashWindowSize = defaultL1BlockHashWindow
+	}
+
+	ethermanHealth := make([]*ethermanStats, len(etherMans))
+	for i := range etherMans {
+		ethermanHealth[i] = newEthermanStats(fmt.Sprintf("etherman-%d", i))
+	}
+
 	return &L1Syncer{
 		etherMans:            etherMans,
 		ethermanIndex:        0,
 		ethermanMtx:          &sync.Mutex{},
+		ethermanHealth:       ethermanHealth,
 		l1ContractAddresses:  l1ContractAddresses,
 		topics:               topics,
 		blockRange:           blockRange,
 		queryDelay:           queryDelay,
 		l1QueryBlocksThreads: l1QueryBlocksThreads,
+		retryPolicy:          DefaultRetryPolicy(),
+		hashWindowSize:       hashWindowSize,
+		confirmationDepth:    confirmationDepth,
 		progressMessageChan:  make(chan string),
 		logsChan:             make(chan []ethTypes.Log),
+		reorgChan:            make(chan L1ReorgEvent, 16),
 	}
 }
 
-func (s *L1Syncer) getNextEtherman() IEtherman {
+// pickEtherman selects which etherman endpoint to use for the next call. It
+// picks among healthy endpoints first, then degraded ones, preferring
+// lower-latency endpoints via weighted random selection so one flaky or slow
+// endpoint can't starve the others. Tripped endpoints are only picked when
+// nothing else is available: one whose cooldown has elapsed first, otherwise
+// round robin. The
+// returned index must be passed to recordEthermanResult once the call
+// completes.
+func (s *L1Syncer) pickEtherman() (int, IEtherman) {
 	s.ethermanMtx.Lock()
 	defer s.ethermanMtx.Unlock()
 
-	if s.ethermanIndex >= uint8(len(s.etherMans)) {
-		s.ethermanIndex = 0
+	type candidate struct {
+		idx     int
+		latency time.Duration
+	}
+
+	var healthy, degraded []candidate
+	trippedButReady := -1
+
+	for i, st := range s.ethermanHealth {
+		if st.isTrippedAndReady() {
+			trippedButReady = i
+			continue
+		}
+		st.mtx.Lock()
+		state := st.state
+		latency := st.avgLatency
+		st.mtx.Unlock()
+		switch state {
+		case ethermanTripped:
+			continue
+		case ethermanDegraded:
+			degraded = append(degraded, candidate{idx: i, latency: latency})
+		default:
+			healthy = append(healthy, candidate{idx: i, latency: latency})
+		}
+	}
+
+	if len(healthy) == 0 {
+		// degraded endpoints are only used once no healthy one is left
+		healthy = degraded
+	}
+
+	if len(healthy) == 0 {
+		if trippedButReady != -1 {
+			return trippedButReady, s.etherMans[trippedButReady]
+		}
+		// every endpoint is tripped and still cooling down - fall back to
+		// round robin rather than stalling the syncer entirely
+		idx := int(s.ethermanIndex) % len(s.etherMans)
+		s.ethermanIndex++
+		return idx, s.etherMans[idx]
+	}
+
+	if len(healthy) == 1 {
+		return healthy[0].idx, s.etherMans[healthy[0].idx]
+	}
+
+	sort.Slice(healthy, func(a, b int) bool { return healthy[a].latency < healthy[b].latency })
+
+	weights := make([]float64, len(healthy))
+	var total float64
+	for i, c := range healthy {
+		latency := c.latency
+		if latency <= 0 {
+			// unmeasured endpoints get the benefit of the doubt
+			latency = time.Millisecond
+		}
+		weights[i] = 1 / float64(latency)
+		total += weights[i]
+	}
+
+	r := rand.Float64() * total
+	for i, w := range weights {
+		r -= w
+		if r <= 0 {
+			return healthy[i].idx, s.etherMans[healthy[i].idx]
+		}
 	}
 
-	etherman := s.etherMans[s.ethermanIndex]
-	s.ethermanIndex++
+	return healthy[0].idx, s.etherMans[healthy[0].idx]
+}
+
+// recordEthermanResult feeds the outcome of a call made against the endpoint
+// returned by pickEtherman back into its health stats.
+func (s *L1Syncer) recordEthermanResult(idx int, start time.Time, err error) {
+	st := s.ethermanHealth[idx]
+	if err != nil {
+		st.recordFailure(err)
+		return
+	}
+	st.recordSuccess(time.Since(start))
+}
 
-	return etherman
+// EndpointStats returns a snapshot of every configured L1 endpoint's health,
+// for operators mixing providers with very different rate limits.
+func (s *L1Syncer) EndpointStats() []EndpointStats {
+	stats := make([]EndpointStats, len(s.ethermanHealth))
+	for i, st := range s.ethermanHealth {
+		stats[i] = st.snapshot()
+	}
+	return stats
+}
+
+// startHealthProbe periodically issues a cheap BlockByNumber(latest) against
+// tripped endpoints whose cooldown has elapsed, so they can recover and
+// rejoin rotation without waiting for organic traffic to hit them again.
+func (s *L1Syncer) startHealthProbe() {
+	go func() {
+		ticker := time.NewTicker(healthProbeInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			for i, st := range s.ethermanHealth {
+				if !st.isTrippedAndReady() {
+					continue
+				}
+
+				start := time.Now()
+				_, err := s.etherMans[i].BlockByNumber(context.Background(), big.NewInt(rpc.LatestBlockNumber.Int64()))
+				s.recordEthermanResult(i, start, err)
+			}
+		}
+	}()
 }
 
 func (s *L1Syncer) IsSyncStarted() bool {
@@ -118,6 +304,79 @@ func (s *L1Syncer) GetProgressMessageChan() chan string {
 	return s.progressMessageChan
 }
 
+// GetReorgChan returns the channel L1ReorgEvents are emitted on when
+// checkForReorg detects that previously-seen L1 blocks are no longer canonical.
+func (s *L1Syncer) GetReorgChan() chan L1ReorgEvent {
+	return s.reorgChan
+}
+
+// emitReorg publishes ev on reorgChan without blocking the sync loop: the
+// channel is buffered, but a caller that never reads GetReorgChan() must not
+// be able to wedge reorg detection (and therefore lastCheckedL1Block rewinds)
+// forever, so a full buffer just drops the event with a log instead of
+// blocking.
+func (s *L1Syncer) emitReorg(ev L1ReorgEvent) {
+	select {
+	case s.reorgChan <- ev:
+	default:
+		log.Warn("L1 reorg channel full, dropping event - is GetReorgChan() being consumed?", "from", ev.FromBlock, "to", ev.ToBlock)
+	}
+}
+
+// GetL1BlockHashWindow returns a copy of the current rolling window of
+// recently seen L1 block hashes, so a caller can persist it alongside
+// lastCheckedL1Block and hand it back via SetL1BlockHashWindow on restart.
+func (s *L1Syncer) GetL1BlockHashWindow() []L1BlockHash {
+	s.hashWindowMtx.Lock()
+	defer s.hashWindowMtx.Unlock()
+	return append([]L1BlockHash(nil), s.hashWindow...)
+}
+
+// SetL1BlockHashWindow restores a previously persisted rolling window. Call
+// this before Run so reorg detection doesn't have to rebuild its history from
+// scratch after a restart.
+func (s *L1Syncer) SetL1BlockHashWindow(window []L1BlockHash) {
+	s.hashWindowMtx.Lock()
+	defer s.hashWindowMtx.Unlock()
+	s.hashWindow = append([]L1BlockHash(nil), window...)
+}
+
+// SetRetryPolicy overrides the default retry/backoff policy used by
+// getSequencedLogs for generic (non size-related) errors.
+func (s *L1Syncer) SetRetryPolicy(policy RetryPolicy) {
+	s.retryPolicy = policy
+}
+
+// SetSubscriptionMode switches Run between polling (the default) and
+// eth_subscribe-based streaming. It must be called before Run.
+func (s *L1Syncer) SetSubscriptionMode(enabled bool) {
+	s.useSubscriptions = enabled
+}
+
+// SetCache attaches a persistent log/header cache so restarts don't have to
+// rescan the whole gap between lastCheckedL1Block and the L1 tip. Pass nil to
+// disable caching.
+func (s *L1Syncer) SetCache(cache *L1Cache) {
+	s.cache = cache
+}
+
+// cacheBoundary returns the highest L1 block number currently considered
+// immutable: s.latestL1Block already accounts for either the node's
+// "finalized" tag or confirmationDepth (see getLatestL1Block), so it doubles
+// as the cache's finality gate.
+func (s *L1Syncer) cacheBoundary() uint64 {
+	return s.latestL1Block
+}
+
+// PruneCache drops every cached log/header at or above belowBlock, e.g. after
+// a reorg invalidates that range. It's a no-op if no cache is attached.
+func (s *L1Syncer) PruneCache(belowBlock uint64) error {
+	if s.cache == nil {
+		return nil
+	}
+	return s.cache.Prune(belowBlock)
+}
+
 func (s *L1Syncer) Run(lastCheckedBlock uint64) {
 	//if already started, don't start another thread
 	if s.isSyncStarted.Load() {
@@ -136,35 +395,184 @@ func (s *L1Syncer) Run(lastCheckedBlock uint64) {
 		log.Info("Starting L1 syncer thread")
 		defer log.Info("Stopping L1 syncer thread")
 
-		for {
-			latestL1Block, err := s.getLatestL1Block()
-			if err != nil {
-				log.Error("Error getting latest L1 block", "err", err)
-			} else {
-				if latestL1Block > s.lastCheckedL1Block.Load() {
-					s.isDownloading.Store(true)
-					if err := s.queryBlocks(); err != nil {
-						log.Error("Error querying blocks", "err", err)
-					} else {
-						s.lastCheckedL1Block.Store(latestL1Block)
-					}
-				}
-			}
+		s.startHealthProbe()
 
+		if s.useSubscriptions {
+			s.runSubscriptions()
+			return
+		}
+
+		s.runPolling()
+	}()
+}
+
+// runPolling is the original timer-driven sync loop: poll for the latest L1
+// block, check for reorgs, and fetch any new logs since lastCheckedL1Block.
+func (s *L1Syncer) runPolling() {
+	for {
+		latestL1Block, err := s.getLatestL1Block()
+		if err != nil {
+			log.Error("Error getting latest L1 block", "err", err)
 			s.isDownloading.Store(false)
 			time.Sleep(time.Duration(s.queryDelay) * time.Millisecond)
+			continue
 		}
-	}()
+
+		if reorged, ancestor, err := s.checkForReorg(); err != nil {
+			log.Error("Error checking for L1 reorg", "err", err)
+		} else if reorged {
+			log.Warn("L1 reorg detected, rewinding lastCheckedL1Block", "to", ancestor)
+			s.lastCheckedL1Block.Store(ancestor)
+		}
+
+		if latestL1Block > s.lastCheckedL1Block.Load() {
+			s.isDownloading.Store(true)
+			if err := s.queryBlocks(); err != nil {
+				log.Error("Error querying blocks", "err", err)
+			} else {
+				s.lastCheckedL1Block.Store(latestL1Block)
+				s.pushHashWindow(latestL1Block, s.latestL1BlockHash)
+			}
+		}
+
+		s.isDownloading.Store(false)
+		time.Sleep(time.Duration(s.queryDelay) * time.Millisecond)
+	}
+}
+
+// runSubscriptions streams new L1 logs and heads via eth_subscribe instead of
+// polling on a timer. If the picked endpoint doesn't support notifications it
+// falls back to runPolling for good; if an established subscription drops, it
+// reconnects (picking a potentially different endpoint) and keeps retrying.
+func (s *L1Syncer) runSubscriptions() {
+	for {
+		err := s.subscribeAndStream()
+		if err == nil {
+			return
+		}
+
+		if errors.Is(err, rpc.ErrNotificationsUnsupported) {
+			log.Warn("L1 endpoint doesn't support subscriptions, falling back to polling", "err", err)
+			s.runPolling()
+			return
+		}
+
+		log.Error("L1 subscription dropped, reconnecting", "err", err)
+		s.isDownloading.Store(false)
+		time.Sleep(time.Duration(s.queryDelay) * time.Millisecond)
+	}
+}
+
+// subscribeAndStream subscribes to logs and new heads on a single picked
+// endpoint, does a bounded catch-up FilterLogs from lastCheckedL1Block, and
+// then streams until a subscription errors or its channel closes. Each
+// received log is sent on logsChan as its own single-element slice, in the
+// same []ethTypes.Log shape the downstream stages already consume. Every
+// incoming head runs the same checkForReorg check runPolling does, so an L1
+// reorg during streaming gets detected and rewound instead of silently
+// accepted.
+func (s *L1Syncer) subscribeAndStream() error {
+	idx, em := s.pickEtherman()
+
+	logCh := make(chan ethTypes.Log, 256)
+	headCh := make(chan *ethTypes.Header, 16)
+
+	logSub, err := em.SubscribeFilterLogs(context.Background(), ethereum.FilterQuery{
+		Addresses: s.l1ContractAddresses,
+		Topics:    s.topics,
+	}, logCh)
+	if err != nil {
+		s.recordEthermanResult(idx, time.Now(), err)
+		return err
+	}
+	defer logSub.Unsubscribe()
+
+	headSub, err := em.SubscribeNewHead(context.Background(), headCh)
+	if err != nil {
+		s.recordEthermanResult(idx, time.Now(), err)
+		return err
+	}
+	defer headSub.Unsubscribe()
+
+	s.recordEthermanResult(idx, time.Now(), nil)
+
+	// bounded catch-up: fetch anything missed since the last checkpoint the
+	// old way before relying purely on the stream going forward
+	if _, err := s.getLatestL1Block(); err != nil {
+		return fmt.Errorf("catch-up: %w", err)
+	}
+	if s.latestL1Block > s.lastCheckedL1Block.Load() {
+		s.isDownloading.Store(true)
+		if err := s.queryBlocks(); err != nil {
+			log.Error("error catching up before switching to subscription mode", "err", err)
+		} else {
+			s.lastCheckedL1Block.Store(s.latestL1Block)
+			s.pushHashWindow(s.latestL1Block, s.latestL1BlockHash)
+		}
+		s.isDownloading.Store(false)
+	}
+
+	for {
+		select {
+		case l, ok := <-logCh:
+			if !ok {
+				return fmt.Errorf("log subscription channel closed")
+			}
+			s.logsChan <- []ethTypes.Log{l}
+		case h, ok := <-headCh:
+			if !ok {
+				return fmt.Errorf("new heads subscription channel closed")
+			}
+			number := h.Number.Uint64()
+
+			if reorged, ancestor, err := s.checkForReorg(); err != nil {
+				log.Error("Error checking for L1 reorg while streaming", "err", err)
+			} else if reorged {
+				log.Warn("L1 reorg detected while streaming, rewinding lastCheckedL1Block", "to", ancestor)
+				s.lastCheckedL1Block.Store(ancestor)
+			}
+
+			if number > s.lastCheckedL1Block.Load() {
+				s.lastCheckedL1Block.Store(number)
+			}
+			s.pushHashWindow(number, h.Hash())
+		case err := <-logSub.Err():
+			return fmt.Errorf("log subscription error: %w", err)
+		case err := <-headSub.Err():
+			return fmt.Errorf("new head subscription error: %w", err)
+		}
+	}
 }
 
 func (s *L1Syncer) GetBlock(number uint64) (*ethTypes.Block, error) {
-	em := s.getNextEtherman()
-	return em.BlockByNumber(context.Background(), new(big.Int).SetUint64(number))
+	if s.cache != nil {
+		if block, ok, err := s.cache.GetBlock(number, s.cacheBoundary()); err != nil {
+			log.Debug("L1 cache read error", "err", err)
+		} else if ok {
+			return block, nil
+		}
+	}
+
+	idx, em := s.pickEtherman()
+	start := time.Now()
+	block, err := em.BlockByNumber(context.Background(), new(big.Int).SetUint64(number))
+	s.recordEthermanResult(idx, start, err)
+
+	if err == nil && s.cache != nil {
+		if cerr := s.cache.CacheBlock(block, s.cacheBoundary()); cerr != nil {
+			log.Debug("L1 cache write error", "err", cerr)
+		}
+	}
+
+	return block, err
 }
 
 func (s *L1Syncer) GetTransaction(hash common.Hash) (ethTypes.Transaction, bool, error) {
-	em := s.getNextEtherman()
-	return em.TransactionByHash(context.Background(), hash)
+	idx, em := s.pickEtherman()
+	start := time.Now()
+	tx, isPending, err := em.TransactionByHash(context.Background(), hash)
+	s.recordEthermanResult(idx, start, err)
+	return tx, isPending, err
 }
 
 func (s *L1Syncer) GetOldAccInputHash(ctx context.Context, addr *common.Address, rollupId, batchNum uint64) (common.Hash, error) {
@@ -266,66 +674,212 @@ func tryToLogL1QueryBlocks(logPrefix string, current, total, threadNum int, dura
 }
 
 func (s *L1Syncer) getLatestL1Block() (uint64, error) {
-	em := s.getNextEtherman()
-	latestBlock, err := em.BlockByNumber(context.Background(), big.NewInt(rpc.FinalizedBlockNumber.Int64()))
+	idx, em := s.pickEtherman()
+	start := time.Now()
+
+	target := big.NewInt(rpc.FinalizedBlockNumber.Int64())
+	if s.confirmationDepth > 0 {
+		target = big.NewInt(rpc.LatestBlockNumber.Int64())
+	}
+
+	latestBlock, err := em.BlockByNumber(context.Background(), target)
 	if err != nil {
+		s.recordEthermanResult(idx, start, err)
 		return 0, err
 	}
 
 	latest := latestBlock.NumberU64()
+	latestHash := latestBlock.Hash()
+
+	if s.confirmationDepth > 0 {
+		if latest > s.confirmationDepth {
+			latest -= s.confirmationDepth
+		} else {
+			latest = 0
+		}
+
+		// the hash we checkpoint is the confirmed block's hash, not the raw tip's,
+		// since that's what we compare against on the next poll
+		confirmedBlock, err := em.BlockByNumber(context.Background(), new(big.Int).SetUint64(latest))
+		if err != nil {
+			s.recordEthermanResult(idx, start, err)
+			return 0, err
+		}
+		latestHash = confirmedBlock.Hash()
+	}
+
+	s.recordEthermanResult(idx, start, nil)
+
 	s.latestL1Block = latest
+	s.latestL1BlockHash = latestHash
 
 	return latest, nil
 }
 
+// pushHashWindow records the hash seen for blockNumber, trimming the window
+// down to hashWindowSize entries.
+func (s *L1Syncer) pushHashWindow(blockNumber uint64, hash common.Hash) {
+	s.hashWindowMtx.Lock()
+	defer s.hashWindowMtx.Unlock()
+
+	if n := len(s.hashWindow); n > 0 && s.hashWindow[n-1].Number == blockNumber {
+		s.hashWindow[n-1].Hash = hash
+	} else {
+		s.hashWindow = append(s.hashWindow, L1BlockHash{Number: blockNumber, Hash: hash})
+	}
+
+	if uint64(len(s.hashWindow)) > s.hashWindowSize {
+		s.hashWindow = s.hashWindow[uint64(len(s.hashWindow))-s.hashWindowSize:]
+	}
+}
+
+// checkForReorg re-fetches the blocks in our hash window, from the tip
+// backwards, looking for the first one whose hash still matches. If the tip
+// itself still matches there's no reorg. If an earlier entry matches, every
+// block after it has been invalidated: a L1ReorgEvent is emitted on
+// reorgChan and the window is truncated to the common ancestor. If nothing in
+// the window matches anymore, the reorg goes deeper than we have history for,
+// so a L1ReorgEvent covering the whole window is emitted and we fall back to
+// rewinding to the oldest block we know about.
+func (s *L1Syncer) checkForReorg() (bool, uint64, error) {
+	s.hashWindowMtx.Lock()
+	window := append([]L1BlockHash(nil), s.hashWindow...)
+	s.hashWindowMtx.Unlock()
+
+	if len(window) == 0 {
+		return false, 0, nil
+	}
+
+	idx, em := s.pickEtherman()
+
+	currentHashes := make([]common.Hash, len(window))
+	for i := len(window) - 1; i >= 0; i-- {
+		start := time.Now()
+		block, err := em.BlockByNumber(context.Background(), new(big.Int).SetUint64(window[i].Number))
+		s.recordEthermanResult(idx, start, err)
+		if err != nil {
+			return false, 0, err
+		}
+		currentHashes[i] = block.Hash()
+
+		if block.Hash() != window[i].Hash {
+			continue
+		}
+
+		if i == len(window)-1 {
+			// tip unchanged, nothing reorged
+			return false, 0, nil
+		}
+
+		invalidated := window[i+1:]
+		oldHashes := make([]common.Hash, len(invalidated))
+		newHashes := make([]common.Hash, len(invalidated))
+		for j, entry := range invalidated {
+			oldHashes[j] = entry.Hash
+			start := time.Now()
+			newBlock, err := em.BlockByNumber(context.Background(), new(big.Int).SetUint64(entry.Number))
+			s.recordEthermanResult(idx, start, err)
+			if err != nil {
+				return false, 0, err
+			}
+			newHashes[j] = newBlock.Hash()
+		}
+
+		if err := s.PruneCache(invalidated[0].Number); err != nil {
+			log.Error("error pruning L1 cache after reorg", "err", err)
+		}
+
+		s.emitReorg(L1ReorgEvent{
+			FromBlock: invalidated[0].Number,
+			ToBlock:   invalidated[len(invalidated)-1].Number,
+			OldHashes: oldHashes,
+			NewHashes: newHashes,
+		})
+
+		s.hashWindowMtx.Lock()
+		s.hashWindow = window[:i+1]
+		s.hashWindowMtx.Unlock()
+
+		return true, window[i].Number, nil
+	}
+
+	// reorg goes deeper than our window - rewind to the oldest block we still have history for
+	if err := s.PruneCache(window[0].Number); err != nil {
+		log.Error("error pruning L1 cache after deep reorg", "err", err)
+	}
+
+	oldHashes := make([]common.Hash, len(window))
+	for j, entry := range window {
+		oldHashes[j] = entry.Hash
+	}
+	s.emitReorg(L1ReorgEvent{
+		FromBlock: window[0].Number,
+		ToBlock:   window[len(window)-1].Number,
+		OldHashes: oldHashes,
+		NewHashes: currentHashes,
+	})
+
+	s.hashWindowMtx.Lock()
+	s.hashWindow = nil
+	s.hashWindowMtx.Unlock()
+
+	return true, window[0].Number, nil
+}
+
 func (s *L1Syncer) queryBlocks() error {
 	startBlock := s.lastCheckedL1Block.Load()
 
 	log.Debug("GetHighestSequence", "startBlock", s.lastCheckedL1Block.Load())
 
-	// define the blocks we're going to fetch up front
-	fetches := make([]fetchJob, 0)
+	stop := make(chan bool)
+	jobs := make(chan fetchJob, 4096)
+	results := make(chan jobResult, 4096)
+
+	// pending tracks how many fetchJobs are still queued or in flight. It's
+	// incremented by push and decremented once a job either reaches a
+	// terminal result or is bisected/requeued (see processFetchJob and
+	// bisectAndRequeue), so it reaches zero exactly when there's nothing left
+	// to do, even though jobs are split and requeued dynamically rather than
+	// known as a fixed count up front. Replacement jobs are always pushed
+	// (incrementing pending) before the job they replace is decremented, so
+	// pending can never be observed at zero while replacement work hasn't
+	// been accounted for yet.
+	var pending int64
+	push := func(j fetchJob) {
+		atomic.AddInt64(&pending, 1)
+		jobs <- j
+	}
+
+	for i := 0; i < batchWorkers; i++ {
+		go s.getSequencedLogs(jobs, results, stop, &pending, push)
+	}
+
+	// queue every initial fetch job up front off the configured blockRange;
+	// per-endpoint learned safe ranges refine this further, by bisecting a
+	// job, once a worker picks an endpoint to serve it
 	low := startBlock
-	for {
+	for low <= s.latestL1Block {
 		high := low + s.blockRange
 		if high > s.latestL1Block {
-			// at the end of our search
 			high = s.latestL1Block
 		}
 
-		fetches = append(fetches, fetchJob{
-			From: low,
-			To:   high,
-		})
+		push(fetchJob{From: low, To: high})
 
 		if high == s.latestL1Block {
 			break
 		}
-		low += s.blockRange + 1
-	}
-
-	stop := make(chan bool)
-	jobs := make(chan fetchJob, len(fetches))
-	results := make(chan jobResult, len(fetches))
-
-	for i := 0; i < batchWorkers; i++ {
-		go s.getSequencedLogs(jobs, results, stop)
-	}
-
-	for _, fetch := range fetches {
-		jobs <- fetch
+		low = high + 1
 	}
-	close(jobs)
 
 	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
 	var progress uint64 = 0
 	aimingFor := s.latestL1Block - startBlock
-	complete := 0
 loop:
 	for {
 		select {
 		case res := <-results:
-			complete++
 			if res.Error != nil {
 				close(stop)
 				return res.Error
@@ -335,12 +889,18 @@ loop:
 				s.logsChan <- res.Logs
 			}
 
-			if complete == len(fetches) {
+			if atomic.LoadInt64(&pending) == 0 {
 				// we've got all the results we need
 				close(stop)
 				break loop
 			}
 		case <-ticker.C:
+			if atomic.LoadInt64(&pending) == 0 {
+				// the last terminal result can be read above before its worker
+				// has retired it from pending, so check again here
+				close(stop)
+				break loop
+			}
 			if aimingFor == 0 {
 				continue
 			}
@@ -348,10 +908,29 @@ loop:
 		}
 	}
 
-	return nil
+	// pending hitting zero only means every job has been accounted for, not
+	// that every terminal result has been read off the (buffered) channel
+	// yet: two workers can each send a terminal result for the last two jobs
+	// at nearly the same time, and this loop only ever checks pending right
+	// after consuming one of them. Drain whatever else is already sitting in
+	// results so a concurrent completion can't be silently dropped.
+	for {
+		select {
+		case res := <-results:
+			if res.Error != nil {
+				return res.Error
+			}
+			progress += res.Size
+			if len(res.Logs) > 0 {
+				s.logsChan <- res.Logs
+			}
+		default:
+			return nil
+		}
+	}
 }
 
-func (s *L1Syncer) getSequencedLogs(jobs <-chan fetchJob, results chan jobResult, stop chan bool) {
+func (s *L1Syncer) getSequencedLogs(jobs <-chan fetchJob, results chan<- jobResult, stop chan bool, pending *int64, push func(fetchJob)) {
 	for {
 		select {
 		case <-stop:
@@ -360,53 +939,123 @@ func (s *L1Syncer) getSequencedLogs(jobs <-chan fetchJob, results chan jobResult
 			if !ok {
 				return
 			}
-			query := ethereum.FilterQuery{
-				FromBlock: new(big.Int).SetUint64(j.From),
-				ToBlock:   new(big.Int).SetUint64(j.To),
-				Addresses: s.l1ContractAddresses,
-				Topics:    s.topics,
-			}
+			s.processFetchJob(j, results, push, pending)
+		}
+	}
+}
 
-			var logs []ethTypes.Log
-			var err error
-			retry := 0
-			for {
-				em := s.getNextEtherman()
-				logs, err = em.FilterLogs(context.Background(), query)
-				if err != nil {
-					log.Debug("getSequencedLogs retry error", "err", err)
-					retry++
-					if retry > 5 {
-						results <- jobResult{
-							Error: err,
-							Logs:  nil,
-						}
-						return
-					}
-					time.Sleep(time.Duration(retry*2) * time.Second)
-					continue
-				}
-				break
-			}
+// processFetchJob fetches a single block range of logs. If the picked
+// endpoint has already learned it can't serve a range this wide, or the
+// provider rejects the request with a size-class error ("response too
+// large", "query returned more than N results", "exceeds the max block
+// range"), the job is bisected and both halves are requeued instead of
+// failing outright. Other errors go through RetryPolicy-governed backoff.
+// Every exit path accounts for j exactly once in pending: a terminal result
+// decrements it only after the result has been sent, and a requeue (bisect
+// or retry) decrements it only after the replacement job(s) have been
+// pushed, so pending is never observed at zero with unaccounted-for work
+// still outstanding.
+func (s *L1Syncer) processFetchJob(j fetchJob, results chan<- jobResult, push func(fetchJob), pending *int64) {
+	if s.cache != nil {
+		if logs, ok, err := s.cache.GetLogsForRange(j.From, j.To, s.cacheBoundary()); err != nil {
+			log.Debug("L1 cache read error", "err", err)
+		} else if ok {
+			results <- jobResult{Size: j.To - j.From, Logs: logs}
+			atomic.AddInt64(pending, -1)
+			return
+		}
+	}
+
+	idx, em := s.pickEtherman()
+	health := s.ethermanHealth[idx]
+
+	if safe := health.currentSafeRange(s.blockRange); j.To-j.From > safe {
+		s.bisectAndRequeue(j, fmt.Errorf("range %d-%d exceeds endpoint %s's learned safe range %d", j.From, j.To, health.label, safe), results, push, pending)
+		return
+	}
+
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(j.From),
+		ToBlock:   new(big.Int).SetUint64(j.To),
+		Addresses: s.l1ContractAddresses,
+		Topics:    s.topics,
+	}
+
+	start := time.Now()
+	logs, err := em.FilterLogs(context.Background(), query)
+	s.recordEthermanResult(idx, start, err)
 
-			results <- jobResult{
-				Size:  j.To - j.From,
-				Error: nil,
-				Logs:  logs,
+	if err == nil {
+		health.recordRangeSuccess(j.To-j.From, s.blockRange)
+		if s.cache != nil {
+			if cerr := s.cache.CacheLogsForRange(j.From, j.To, s.cacheBoundary(), logs); cerr != nil {
+				log.Debug("L1 cache write error", "err", cerr)
 			}
 		}
+		results <- jobResult{Size: j.To - j.From, Logs: logs}
+		atomic.AddInt64(pending, -1)
+		return
+	}
+
+	if class := classifyLogsError(err); class.isSizeError() {
+		newRange := health.recordRangeFailure(j.To - j.From)
+		log.Debug("getSequencedLogs range rejected, bisecting", "endpoint", health.label, "from", j.From, "to", j.To, "learnedSafeRange", newRange, "err", err)
+		s.bisectAndRequeue(j, err, results, push, pending)
+		return
 	}
+
+	j.Attempt++
+	if j.Attempt > s.retryPolicy.MaxAttempts {
+		results <- jobResult{Error: err}
+		atomic.AddInt64(pending, -1)
+		return
+	}
+
+	log.Debug("getSequencedLogs retry error", "err", err, "attempt", j.Attempt)
+	time.Sleep(s.retryPolicy.delay(j.Attempt))
+	push(j)
+	atomic.AddInt64(pending, -1)
+}
+
+// bisectAndRequeue splits a fetchJob into two halves and requeues them. A
+// single-block job can't be split any further, so instead it's requeued as-is
+// with its attempt count bumped - unless it has already exhausted
+// s.retryPolicy.MaxAttempts, in which case err is surfaced as a terminal
+// jobResult instead of requeuing it forever (a single block that keeps
+// rejecting with a size-class error would otherwise loop here indefinitely,
+// since that error class never reaches processFetchJob's generic retry path).
+// The replacement job(s) are pushed before j is retired from pending, so
+// pending can't be observed at zero in the gap between the two.
+func (s *L1Syncer) bisectAndRequeue(j fetchJob, err error, results chan<- jobResult, push func(fetchJob), pending *int64) {
+	defer atomic.AddInt64(pending, -1)
+
+	if j.To <= j.From {
+		j.Attempt++
+		if j.Attempt > s.retryPolicy.MaxAttempts {
+			log.Debug("getSequencedLogs single-block range still rejected after max attempts, giving up", "from", j.From, "attempt", j.Attempt, "err", err)
+			results <- jobResult{Error: err}
+			return
+		}
+		push(j)
+		return
+	}
+
+	mid := j.From + (j.To-j.From)/2
+	push(fetchJob{From: j.From, To: mid})
+	push(fetchJob{From: mid + 1, To: j.To})
 }
 
 func (s *L1Syncer) callGetRollupSequencedBatches(ctx context.Context, addr *common.Address, rollupId, batchNum uint64) (common.Hash, uint64, error) {
 	rollupID := fmt.Sprintf("%064x", rollupId)
 	batchNumber := fmt.Sprintf("%064x", batchNum)
 
-	em := s.getNextEtherman()
+	idx, em := s.pickEtherman()
+	start := time.Now()
 	resp, err := em.CallContract(ctx, ethereum.CallMsg{
 		To:   addr,
 		Data: common.FromHex(rollupSequencedBatchesSignature + rollupID + batchNumber),
 	}, nil)
+	s.recordEthermanResult(idx, start, err)
 
 	if err != nil {
 		return common.Hash{}, 0, err
@@ -0,0 +1,374 @@
+package syncer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gateway-fm/cdk-erigon-lib/common"
+	ethereum "github.com/ledgerwatch/erigon"
+	ethTypes "github.com/ledgerwatch/erigon/core/types"
+	"github.com/ledgerwatch/erigon/rpc"
+)
+
+// reorgFakeEtherman serves BlockByNumber from a fixed map, returns empty
+// results for the other calls and rejects subscriptions as a non-subscribing
+// endpoint, for exercising checkForReorg in isolation.
+type reorgFakeEtherman struct {
+	blocks map[uint64]*ethTypes.Block
+}
+
+func (f *reorgFakeEtherman) BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error) {
+	return f.blocks[number.Uint64()], nil
+}
+
+func (f *reorgFakeEtherman) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	return nil, nil
+}
+
+func (f *reorgFakeEtherman) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *reorgFakeEtherman) TransactionByHash(ctx context.Context, hash common.Hash) (ethTypes.Transaction, bool, error) {
+	return nil, false, nil
+}
+
+func (f *reorgFakeEtherman) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (f *reorgFakeEtherman) SubscribeNewHead(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func blockWithExtra(number uint64, extra byte) *ethTypes.Block {
+	h := &ethTypes.Header{Number: big.NewInt(int64(number)), Extra: []byte{extra}}
+	return ethTypes.NewBlockWithHeader(h)
+}
+
+func TestCheckForReorgFindsCommonAncestor(t *testing.T) {
+	em := &reorgFakeEtherman{blocks: map[uint64]*ethTypes.Block{}}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 1000, 0, 1, 0, 0)
+
+	// blocks 10, 11, 12 previously seen; 11 and 12 get reorged out, 10 stays
+	// canonical and becomes the common ancestor.
+	old10, old11, old12 := blockWithExtra(10, 1), blockWithExtra(11, 1), blockWithExtra(12, 1)
+	s.pushHashWindow(10, old10.Hash())
+	s.pushHashWindow(11, old11.Hash())
+	s.pushHashWindow(12, old12.Hash())
+
+	em.blocks[10] = old10
+	em.blocks[11] = blockWithExtra(11, 2)
+	em.blocks[12] = blockWithExtra(12, 2)
+
+	reorged, ancestor, err := s.checkForReorg()
+	if err != nil {
+		t.Fatalf("checkForReorg: %v", err)
+	}
+	if !reorged {
+		t.Fatal("expected reorg to be detected")
+	}
+	if ancestor != 10 {
+		t.Fatalf("got ancestor %d, want 10", ancestor)
+	}
+
+	select {
+	case ev := <-s.GetReorgChan():
+		if ev.FromBlock != 11 || ev.ToBlock != 12 {
+			t.Fatalf("got reorg event %+v, want FromBlock=11 ToBlock=12", ev)
+		}
+	default:
+		t.Fatal("expected a reorg event on reorgChan")
+	}
+}
+
+func TestCheckForReorgNoChange(t *testing.T) {
+	em := &reorgFakeEtherman{blocks: map[uint64]*ethTypes.Block{}}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 1000, 0, 1, 0, 0)
+
+	tip := blockWithExtra(5, 1)
+	s.pushHashWindow(5, tip.Hash())
+	em.blocks[5] = tip
+
+	reorged, _, err := s.checkForReorg()
+	if err != nil {
+		t.Fatalf("checkForReorg: %v", err)
+	}
+	if reorged {
+		t.Fatal("expected no reorg when the tip hash is unchanged")
+	}
+}
+
+// TestEmitReorgDoesNotBlockWhenChannelFull guards against the reorgChan
+// deadlock: checkForReorg used to send on an unbuffered channel with nobody
+// required to consume it, wedging the sync loop on the very first reorg.
+func TestEmitReorgDoesNotBlockWhenChannelFull(t *testing.T) {
+	em := &reorgFakeEtherman{}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 1000, 0, 1, 0, 0)
+
+	for i := 0; i < cap(s.reorgChan); i++ {
+		s.emitReorg(L1ReorgEvent{FromBlock: uint64(i)})
+	}
+
+	done := make(chan struct{})
+	go func() {
+		s.emitReorg(L1ReorgEvent{FromBlock: 999})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("emitReorg blocked with a full channel and no consumer")
+	}
+}
+
+// slowFilterEtherman answers FilterLogs after a fixed delay with a single log
+// tagged by the range's FromBlock, and rejects subscriptions so queryBlocks's
+// polling path is exercised.
+type slowFilterEtherman struct {
+	delay time.Duration
+}
+
+func (f *slowFilterEtherman) BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error) {
+	return blockWithExtra(number.Uint64(), 0), nil
+}
+
+func (f *slowFilterEtherman) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	time.Sleep(f.delay)
+	return []ethTypes.Log{{BlockNumber: query.FromBlock.Uint64()}}, nil
+}
+
+func (f *slowFilterEtherman) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *slowFilterEtherman) TransactionByHash(ctx context.Context, hash common.Hash) (ethTypes.Transaction, bool, error) {
+	return nil, false, nil
+}
+
+func (f *slowFilterEtherman) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (f *slowFilterEtherman) SubscribeNewHead(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+// TestQueryBlocksDoesNotDropConcurrentFinalResults guards against the
+// queryBlocks/getSequencedLogs dropped-result bug: with batchWorkers==2 and
+// every FilterLogs call taking the same fixed delay, the last two jobs
+// finish at nearly the same instant, which used to let pending hit zero
+// after consuming only the first of the two and silently drop the second.
+func TestQueryBlocksDoesNotDropConcurrentFinalResults(t *testing.T) {
+	em := &slowFilterEtherman{delay: 20 * time.Millisecond}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 9, 0, 1, 0, 0)
+	s.latestL1Block = 39 // blockRange 9 over [0,39] -> 4 jobs of 10 blocks each
+
+	seen := make(map[uint64]bool)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for logs := range s.GetLogsChan() {
+			mu.Lock()
+			for _, l := range logs {
+				seen[l.BlockNumber] = true
+			}
+			mu.Unlock()
+		}
+	}()
+	go func() {
+		for range s.GetProgressMessageChan() {
+		}
+	}()
+
+	if err := s.queryBlocks(); err != nil {
+		t.Fatalf("queryBlocks: %v", err)
+	}
+	close(s.logsChan)
+	wg.Wait()
+
+	want := []uint64{0, 10, 20, 30}
+	for _, from := range want {
+		if !seen[from] {
+			t.Fatalf("missing logs for job starting at block %d - got %v", from, seen)
+		}
+	}
+}
+
+// sizeErrorEtherman's FilterLogs always rejects with a size-class error, for
+// exercising the single-block bisectAndRequeue terminal-failure path.
+type sizeErrorEtherman struct{}
+
+func (f *sizeErrorEtherman) BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error) {
+	return blockWithExtra(number.Uint64(), 0), nil
+}
+
+func (f *sizeErrorEtherman) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	return nil, errors.New("response too large")
+}
+
+func (f *sizeErrorEtherman) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *sizeErrorEtherman) TransactionByHash(ctx context.Context, hash common.Hash) (ethTypes.Transaction, bool, error) {
+	return nil, false, nil
+}
+
+func (f *sizeErrorEtherman) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+func (f *sizeErrorEtherman) SubscribeNewHead(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	return nil, rpc.ErrNotificationsUnsupported
+}
+
+// TestQueryBlocksTerminatesOnPersistentSingleBlockSizeError guards against the
+// bisectAndRequeue infinite loop: a single-block job that always comes back
+// as a size-class error can't be split any smaller, so it used to be
+// requeued forever (pending never reaching 0, queryBlocks never returning)
+// instead of ever reaching the MaxAttempts check.
+func TestQueryBlocksTerminatesOnPersistentSingleBlockSizeError(t *testing.T) {
+	em := &sizeErrorEtherman{}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 1000, 0, 1, 0, 0)
+	s.latestL1Block = 0 // startBlock==latestL1Block==0 forces a single-block job
+
+	go func() {
+		for range s.GetLogsChan() {
+		}
+	}()
+	go func() {
+		for range s.GetProgressMessageChan() {
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() { done <- s.queryBlocks() }()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("expected queryBlocks to return an error once MaxAttempts was exhausted")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("queryBlocks hung, bisectAndRequeue likely looping forever on the single-block size error")
+	}
+}
+
+// fakeSubscription is a minimal ethereum.Subscription whose only job is to
+// let a test trigger the "subscription errored" exit path on demand.
+type fakeSubscription struct {
+	errCh chan error
+}
+
+func (f *fakeSubscription) Err() <-chan error { return f.errCh }
+func (f *fakeSubscription) Unsubscribe()       {}
+
+// subFakeEtherman drives subscribeAndStream: BlockByNumber answers the
+// "latest/finalized" probe (any non-positive target) from latest and plain
+// reorg-check lookups from the blocks map, and the two Subscribe* calls hand their
+// channels back to the test so it can push heads/logs directly.
+type subFakeEtherman struct {
+	latest    *ethTypes.Block
+	blocks    map[uint64]*ethTypes.Block
+	headCh    chan<- *ethTypes.Header
+	headErrCh chan error
+	logErrCh  chan error
+}
+
+func (f *subFakeEtherman) BlockByNumber(ctx context.Context, number *big.Int) (*ethTypes.Block, error) {
+	if number.Sign() <= 0 {
+		return f.latest, nil
+	}
+	return f.blocks[number.Uint64()], nil
+}
+
+func (f *subFakeEtherman) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]ethTypes.Log, error) {
+	return nil, nil
+}
+
+func (f *subFakeEtherman) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (f *subFakeEtherman) TransactionByHash(ctx context.Context, hash common.Hash) (ethTypes.Transaction, bool, error) {
+	return nil, false, nil
+}
+
+func (f *subFakeEtherman) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- ethTypes.Log) (ethereum.Subscription, error) {
+	f.logErrCh = make(chan error)
+	return &fakeSubscription{errCh: f.logErrCh}, nil
+}
+
+func (f *subFakeEtherman) SubscribeNewHead(ctx context.Context, ch chan<- *ethTypes.Header) (ethereum.Subscription, error) {
+	f.headCh = ch
+	f.headErrCh = make(chan error)
+	return &fakeSubscription{errCh: f.headErrCh}, nil
+}
+
+// TestSubscribeAndStreamDetectsReorg guards against subscription mode
+// silently accepting an L1 reorg: it used to update lastCheckedL1Block
+// straight off incoming newHeads without ever running checkForReorg.
+func TestSubscribeAndStreamDetectsReorg(t *testing.T) {
+	old5, old6 := blockWithExtra(5, 1), blockWithExtra(6, 1)
+	em := &subFakeEtherman{
+		latest: old6,
+		blocks: map[uint64]*ethTypes.Block{5: old5, 6: old6},
+	}
+	s := NewL1Syncer([]IEtherman{em}, nil, nil, 1000, 0, 1, 0, 0)
+	s.lastCheckedL1Block.Store(6)
+	s.pushHashWindow(5, old5.Hash())
+	s.pushHashWindow(6, old6.Hash())
+
+	done := make(chan error, 1)
+	go func() { done <- s.subscribeAndStream() }()
+
+	// wait for subscriptions to be set up before pushing a head
+	for i := 0; i < 1000 && em.headCh == nil; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if em.headCh == nil {
+		t.Fatal("subscribeAndStream never called SubscribeNewHead")
+	}
+
+	// block 6 gets reorged out before the new head (7) arrives
+	em.blocks[6] = blockWithExtra(6, 2)
+	newHead := &ethTypes.Header{Number: big.NewInt(7)}
+	em.headCh <- newHead
+
+	select {
+	case ev := <-s.GetReorgChan():
+		if ev.FromBlock != 6 || ev.ToBlock != 6 {
+			t.Fatalf("got reorg event %+v, want FromBlock=6 ToBlock=6", ev)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected subscribeAndStream to detect the reorg and emit an event")
+	}
+
+	// the event is emitted before lastCheckedL1Block is rewound and then
+	// advanced to the new head, so give the stream a moment to get there
+	var got uint64
+	for i := 0; i < 1000; i++ {
+		if got = s.GetLastCheckedL1Block(); got == 7 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if got != 7 {
+		t.Fatalf("got lastCheckedL1Block %d, want 7 (advanced past the rewound ancestor)", got)
+	}
+
+	em.headErrCh <- errors.New("stop")
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("subscribeAndStream didn't return after the head subscription errored")
+	}
+}